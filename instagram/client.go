@@ -0,0 +1,166 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	str "strings"
+	"time"
+)
+
+const (
+	libraryVersion   = "0.1"
+	defaultBaseURL   = "https://api.instagram.com/v1/"
+	defaultUserAgent = "go-instagram/" + libraryVersion
+)
+
+// Client manages communication with the Instagram API.
+type Client struct {
+	// HTTP client used to communicate with the API.
+	client *http.Client
+
+	// Base URL for API requests. Defaults to the public Instagram API, but
+	// can be changed for testing.
+	BaseURL *url.URL
+
+	// User agent used when communicating with the API.
+	UserAgent string
+
+	// AccessToken authenticates requests made on behalf of a user.
+	AccessToken string
+
+	// Response is the metadata (including pagination) from the most
+	// recent API call made through this Client.
+	Response *Response
+
+	// Cache, if set, is consulted before issuing read-only relationship
+	// requests and updated after they complete; see relationships_cache.go.
+	Cache Cache
+
+	// CacheTTL is how long entries written to Cache remain fresh.
+	CacheTTL time.Duration
+
+	// Relationships handles communication with the relationship related
+	// methods of the Instagram API.
+	Relationships *RelationshipsService
+}
+
+// Response wraps http.Response and exposes the Instagram-specific
+// pagination metadata that accompanies most list endpoints.
+type Response struct {
+	*http.Response
+
+	Pagination *ResponsePagination
+}
+
+// ResponsePagination represents the pagination metadata returned
+// alongside paginated Instagram API responses.
+type ResponsePagination struct {
+	NextURL    string `json:"next_url,omitempty"`
+	NextMaxID  string `json:"next_max_id,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewClient returns a new Instagram API client. If httpClient is nil,
+// http.DefaultClient is used.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	baseURL, _ := url.Parse(defaultBaseURL)
+
+	c := &Client{client: httpClient, BaseURL: baseURL, UserAgent: defaultUserAgent}
+	c.Relationships = &RelationshipsService{client: c}
+	return c
+}
+
+// NewRequest creates an API request against urn, relative to the Client's
+// BaseURL. body, when non-empty, is a urlencoded "key=value&..." string
+// whose pairs are folded into the request's query string alongside the
+// access token, matching how the relationship endpoints pass their
+// "action" parameter.
+func (c *Client) NewRequest(method, urn, body string) (*http.Request, error) {
+	rel, err := url.Parse(urn)
+	if err != nil {
+		return nil, err
+	}
+	u := c.BaseURL.ResolveReference(rel)
+
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	q := req.URL.Query()
+	if body != "" {
+		for _, pair := range str.Split(body, "&") {
+			kv := str.SplitN(pair, "=", 2)
+			if len(kv) == 2 {
+				q.Set(kv[0], kv[1])
+			}
+		}
+	}
+	if c.AccessToken != "" {
+		q.Set("access_token", c.AccessToken)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	return req, nil
+}
+
+// Do sends an API request and decodes the JSON response into v. The
+// response's pagination metadata, if any, is recorded on c.Response.
+//
+// Do is not safe to call concurrently on the same Client: it stashes the
+// response on c.Response for callers that need the accompanying
+// pagination (e.g. Follows, FollowedBy). Callers that don't need
+// pagination and may be called from multiple goroutines at once, such as
+// BulkAction, should use send instead.
+func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
+	resp, pagination, err := c.send(req, v)
+	if resp != nil {
+		c.Response = &Response{Response: resp, Pagination: pagination}
+	}
+	return resp, err
+}
+
+// send performs req and decodes the JSON response into v, returning the
+// pagination metadata, if any, instead of recording it on the Client.
+// Unlike Do, send touches no shared Client state, so it's safe to call
+// concurrently from multiple goroutines on the same Client.
+func (c *Client) send(req *http.Request, v interface{}) (*http.Response, *ResponsePagination, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return resp, nil, nil
+	}
+
+	envelope := struct {
+		Data       json.RawMessage     `json:"data"`
+		Pagination *ResponsePagination `json:"pagination"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return resp, nil, err
+	}
+
+	if v != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, v); err != nil {
+			return resp, envelope.Pagination, err
+		}
+	}
+
+	return resp, envelope.Pagination, nil
+}
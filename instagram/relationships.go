@@ -6,7 +6,9 @@
 package instagram
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	str "strings"
 )
 
@@ -30,6 +32,34 @@ type Relationship struct {
 	// Undocumented part of the API, though was stable at least from 2012-2015
 	// Informs whether the target user is a private user
 	TargetUserIsPrivate bool `json:"target_user_is_private,omitempty"`
+
+	// The following fields give Relationship the same shape as the
+	// Mastodon/ActivityPub relationship resource (GET /api/v1/accounts/relationships),
+	// so code written against that API stays portable. Instagram has no
+	// equivalent concept for any of them; they are always the zero value
+	// and the methods that would set them return ErrUnsupportedByBackend.
+
+	// Muting reports whether the authenticated user is muting the target user.
+	Muting bool `json:"muting,omitempty"`
+
+	// MutingNotifications reports whether the authenticated user is also
+	// muting notifications from the target user.
+	MutingNotifications bool `json:"muting_notifications,omitempty"`
+
+	// ShowingReblogs reports whether the authenticated user is shown
+	// reblogs/reposts from the target user.
+	ShowingReblogs bool `json:"showing_reblogs,omitempty"`
+
+	// DomainBlocking reports whether the authenticated user is blocking
+	// the target user's domain.
+	DomainBlocking bool `json:"domain_blocking,omitempty"`
+
+	// Endorsed reports whether the authenticated user has endorsed the
+	// target user on their profile.
+	Endorsed bool `json:"endorsed,omitempty"`
+
+	// Note is the authenticated user's private note about the target user.
+	Note string `json:"note,omitempty"`
 }
 
 // Follows gets the list of users curret authenticated user follows.
@@ -40,24 +70,7 @@ func (s *RelationshipsService) Follows(oPagination *ResponsePagination) ([]User,
 	if nil != oPagination {
 		u = str.Replace(oPagination.NextURL, "https://api.instagram.com/v1/", "", -1)
 	}
-	req, err := s.client.NewRequest("GET", u, "")
-	if err != nil {
-		return nil, nil, err
-	}
-
-	users := new([]User)
-
-	_, err = s.client.Do(req, users)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
-	}
-
-	return *users, page, err
+	return s.cachedUsersRequest(u)
 }
 
 // FollowedBy gets the list of users curret authenticated user is followed by.
@@ -68,55 +81,21 @@ func (s *RelationshipsService) FollowedBy(oPagination *ResponsePagination) ([]Us
 	if nil != oPagination {
 		u = str.Replace(oPagination.NextURL, "https://api.instagram.com/v1/", "", -1)
 	}
-	req, err := s.client.NewRequest("GET", u, "")
-	if err != nil {
-		return nil, nil, err
-	}
-
-	users := new([]User)
-
-	_, err = s.client.Do(req, users)
-	if err != nil {
-		return nil, nil, err
-	}
-	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
-	}
-
-	return *users, page, err
+	return s.cachedUsersRequest(u)
 }
 
 // RequestedBy lists the users who have requested this user's permission to follow.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/relationships/#get_incoming_requests
 func (s *RelationshipsService) RequestedBy() ([]User, *ResponsePagination, error) {
-	u := "users/self/requested-by"
-	req, err := s.client.NewRequest("GET", u, "")
-	if err != nil {
-		return nil, nil, err
-	}
-
-	users := new([]User)
-
-	_, err = s.client.Do(req, users)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
-	}
-
-	return *users, page, err
+	return s.cachedUsersRequest("users/self/requested-by")
 }
 
 // Relationship gets information about a relationship to another user.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/relationships/#get_relationship
 func (s *RelationshipsService) Relationship(userID string) (*Relationship, error) {
-	return relationshipAction(s, userID, "", "GET")
+	return s.cachedRelationship(userID)
 }
 
 // Follow a user.
@@ -154,6 +133,45 @@ func (s *RelationshipsService) Approve(userID string) (*Relationship, error) {
 	return relationshipAction(s, userID, "approve", "POST")
 }
 
+// ErrUnsupportedByBackend is returned by methods that exist for
+// compatibility with other ActivityPub/Mastodon-style clients but have no
+// equivalent in the Instagram API.
+var ErrUnsupportedByBackend = errors.New("instagram: not supported by this backend")
+
+// Mute is not supported by the Instagram API and always returns
+// ErrUnsupportedByBackend.
+func (s *RelationshipsService) Mute(userID string) (*Relationship, error) {
+	return nil, ErrUnsupportedByBackend
+}
+
+// Unmute is not supported by the Instagram API and always returns
+// ErrUnsupportedByBackend.
+func (s *RelationshipsService) Unmute(userID string) (*Relationship, error) {
+	return nil, ErrUnsupportedByBackend
+}
+
+// Note is not supported by the Instagram API and always returns
+// ErrUnsupportedByBackend.
+func (s *RelationshipsService) Note(userID, text string) (*Relationship, error) {
+	return nil, ErrUnsupportedByBackend
+}
+
+// Relationships looks up the authenticated user's relationship to each of
+// userIDs. Instagram has no batched equivalent of Mastodon's
+// GET /api/v1/accounts/relationships, so this issues one Relationship call
+// per user; it stops and returns the error from the first call that fails.
+func (s *RelationshipsService) Relationships(userIDs ...string) ([]Relationship, error) {
+	rels := make([]Relationship, 0, len(userIDs))
+	for _, userID := range userIDs {
+		rel, err := s.Relationship(userID)
+		if err != nil {
+			return rels, err
+		}
+		rels = append(rels, *rel)
+	}
+	return rels, nil
+}
+
 // Deny a user.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/relationships/#post_relationship
@@ -162,16 +180,21 @@ func (s *RelationshipsService) Deny(userID string) (*Relationship, error) {
 }
 
 func relationshipAction(s *RelationshipsService, userID, action, method string) (*Relationship, error) {
+	rel, _, err := relationshipActionWithResponse(s, userID, action, method)
+	return rel, err
+}
+
+func relationshipActionWithResponse(s *RelationshipsService, userID, action, method string) (*Relationship, *http.Response, error) {
 	u := fmt.Sprintf("users/%v/relationship", userID)
 	if action != "" {
 		action = "action=" + action
 	}
 	req, err := s.client.NewRequest(method, u, action)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	rel := new(Relationship)
-	_, err = s.client.Do(req, rel)
-	return rel, err
+	resp, _, err := s.client.send(req, rel)
+	return rel, resp, err
 }
@@ -0,0 +1,185 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RelationshipAction identifies the mutation to apply to a relationship in
+// a BulkAction call. The values match the "action" parameter accepted by
+// the single-user relationship endpoint.
+type RelationshipAction string
+
+// Supported bulk relationship actions.
+const (
+	ActionFollow   RelationshipAction = "follow"
+	ActionUnfollow RelationshipAction = "unfollow"
+	ActionBlock    RelationshipAction = "block"
+	ActionUnblock  RelationshipAction = "unblock"
+	ActionApprove  RelationshipAction = "approve"
+	ActionDeny     RelationshipAction = "deny"
+)
+
+// BulkOptions controls how BulkAction fans calls out across the API.
+type BulkOptions struct {
+	// Concurrency is the number of relationship calls allowed in flight at
+	// once. Values <= 0 are treated as 1.
+	Concurrency int
+
+	// MaxRetries is the number of additional attempts made for a user's
+	// call after a 429 or 5xx response, or after a failure with no
+	// response at all, with exponential backoff between attempts.
+	// Values <= 0 disable retries.
+	MaxRetries int
+
+	// MinRateLimitRemaining is the X-Ratelimit-Remaining threshold below
+	// which BulkAction pauses new calls for one minute. 0 disables the
+	// check.
+	MinRateLimitRemaining int
+
+	// DryRun, when true, reports the calls BulkAction would make without
+	// issuing any of them.
+	DryRun bool
+}
+
+// BulkResult reports the outcome of a single user's relationship mutation
+// as part of a BulkAction call.
+type BulkResult struct {
+	UserID       string
+	Relationship *Relationship
+	Err          error
+}
+
+// BulkAction applies action to every user in userIDs, using a worker pool
+// bounded by opts.Concurrency. Calls that fail with a rate-limited or
+// server error are retried with exponential backoff up to
+// opts.MaxRetries times. BulkAction returns one BulkResult per user, in
+// no particular order, and only returns a top-level error if ctx is
+// canceled before all calls complete.
+func (s *RelationshipsService) BulkAction(ctx context.Context, userIDs []string, action RelationshipAction, opts *BulkOptions) ([]BulkResult, error) {
+	if opts == nil {
+		opts = &BulkOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]BulkResult, len(userIDs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var rateMu sync.Mutex
+	var pauseUntil time.Time
+
+	for i, userID := range userIDs {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, userID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if opts.DryRun {
+				results[i] = BulkResult{UserID: userID}
+				return
+			}
+
+			rel, err := s.bulkActionWithRetry(ctx, userID, action, opts, &rateMu, &pauseUntil)
+			results[i] = BulkResult{UserID: userID, Relationship: rel, Err: err}
+		}(i, userID)
+	}
+
+	wg.Wait()
+	return results, ctx.Err()
+}
+
+func (s *RelationshipsService) bulkActionWithRetry(ctx context.Context, userID string, action RelationshipAction, opts *BulkOptions, rateMu *sync.Mutex, pauseUntil *time.Time) (*Relationship, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= opts.MaxRetries; attempt++ {
+		waitForRateLimit(ctx, rateMu, pauseUntil)
+
+		rel, resp, err := s.relationshipActionResponse(userID, string(action))
+		if err == nil && resp != nil && resp.StatusCode/100 != 2 {
+			err = fmt.Errorf("instagram: %s", resp.Status)
+		}
+		if resp != nil {
+			observeRateLimit(resp, opts.MinRateLimitRemaining, rateMu, pauseUntil)
+		}
+
+		if err == nil {
+			return rel, nil
+		}
+		lastErr = err
+
+		if !isRetryable(resp) || attempt == opts.MaxRetries {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff(attempt)):
+		}
+	}
+
+	return nil, lastErr
+}
+
+func (s *RelationshipsService) relationshipActionResponse(userID, action string) (*Relationship, *http.Response, error) {
+	return relationshipActionWithResponse(s, userID, action, "POST")
+}
+
+func isRetryable(resp *http.Response) bool {
+	if resp == nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+func backoff(attempt int) time.Duration {
+	d := time.Duration(1<<uint(attempt)) * 500 * time.Millisecond
+	if max := 30 * time.Second; d > max {
+		d = max
+	}
+	return d
+}
+
+func observeRateLimit(resp *http.Response, min int, rateMu *sync.Mutex, pauseUntil *time.Time) {
+	if min <= 0 {
+		return
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-Ratelimit-Remaining"))
+	if err != nil || remaining >= min {
+		return
+	}
+
+	rateMu.Lock()
+	*pauseUntil = time.Now().Add(time.Minute)
+	rateMu.Unlock()
+}
+
+func waitForRateLimit(ctx context.Context, rateMu *sync.Mutex, pauseUntil *time.Time) {
+	rateMu.Lock()
+	until := *pauseUntil
+	rateMu.Unlock()
+
+	if d := time.Until(until); d > 0 {
+		select {
+		case <-ctx.Done():
+		case <-time.After(d):
+		}
+	}
+}
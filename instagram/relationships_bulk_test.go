@@ -0,0 +1,116 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *http.Response
+		want bool
+	}{
+		{"nil response", nil, true},
+		{"rate limited", &http.Response{StatusCode: http.StatusTooManyRequests}, true},
+		{"server error", &http.Response{StatusCode: http.StatusBadGateway}, true},
+		{"ok", &http.Response{StatusCode: http.StatusOK}, false},
+		{"not found", &http.Response{StatusCode: http.StatusNotFound}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.resp); got != tt.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.resp, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffGrowsAndCaps(t *testing.T) {
+	prev := time.Duration(0)
+	for attempt := 0; attempt < 8; attempt++ {
+		d := backoff(attempt)
+		if d < prev {
+			t.Fatalf("backoff(%d) = %v, want >= previous attempt's %v", attempt, d, prev)
+		}
+		prev = d
+	}
+	if max := 30 * time.Second; prev != max {
+		t.Errorf("backoff should cap at %v, got %v", max, prev)
+	}
+}
+
+func TestObserveRateLimitPausesBelowThreshold(t *testing.T) {
+	var mu sync.Mutex
+	var pauseUntil time.Time
+
+	resp := &http.Response{Header: http.Header{"X-Ratelimit-Remaining": []string{"1"}}}
+	observeRateLimit(resp, 10, &mu, &pauseUntil)
+
+	if !pauseUntil.After(time.Now()) {
+		t.Errorf("expected pauseUntil to be set in the future, got %v", pauseUntil)
+	}
+}
+
+func TestObserveRateLimitIgnoresWhenAboveThreshold(t *testing.T) {
+	var mu sync.Mutex
+	var pauseUntil time.Time
+
+	resp := &http.Response{Header: http.Header{"X-Ratelimit-Remaining": []string{"500"}}}
+	observeRateLimit(resp, 10, &mu, &pauseUntil)
+
+	if !pauseUntil.IsZero() {
+		t.Errorf("expected pauseUntil to stay zero, got %v", pauseUntil)
+	}
+}
+
+// TestBulkActionConcurrentIsRaceFree drives BulkAction with Concurrency
+// greater than 1 so `go test -race` can catch regressions where a worker
+// writes shared Client state (see relationshipActionWithResponse, which
+// must use Client.send rather than Client.Do for exactly this reason).
+func TestBulkActionConcurrentIsRaceFree(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := json.Marshal(struct {
+			Data Relationship `json:"data"`
+		}{Data: Relationship{OutgoingStatus: "follows"}})
+		w.Write(body)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+
+	base, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	c := NewClient(nil)
+	c.BaseURL = base
+
+	userIDs := make([]string, 8)
+	for i := range userIDs {
+		userIDs[i] = "user"
+	}
+
+	results, err := c.Relationships.BulkAction(context.Background(), userIDs, ActionFollow, &BulkOptions{Concurrency: 8})
+	if err != nil {
+		t.Fatalf("BulkAction: %v", err)
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("unexpected per-user error: %v", r.Err)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a single cached response, keyed by request URL, auth user,
+// and pagination cursor. While Expires is in the future, the entry is
+// served with no request to the server at all. Once it isn't, and the
+// Cache still returns it, ETag is sent back as If-None-Match so the server
+// can answer with a 304 instead of re-sending the body. LRUCache drops an
+// entry as soon as its Expires passes, so with LRUCache only entries
+// stored with a zero CacheTTL are revalidated this way.
+type CacheEntry struct {
+	Value   []byte
+	ETag    string
+	Expires time.Time
+}
+
+// Cache is implemented by anything that can store responses for the
+// read-only relationship endpoints (Follows, FollowedBy, RequestedBy,
+// Relationship). LRUCache is the in-memory default; callers can instead
+// plug in Redis, a filesystem cache, or similar by implementing Cache
+// themselves and setting it on Client.
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry, ttl time.Duration)
+	Delete(key string)
+}
+
+// cacheKey identifies a cached relationship read by endpoint, the
+// authenticated user making the request, and, for paginated listings, the
+// cursor being fetched.
+func cacheKey(client *Client, u string) string {
+	return fmt.Sprintf("%s|%s", client.AccessToken, u)
+}
+
+type cachedUsersPage struct {
+	Users      []User
+	Pagination *ResponsePagination
+}
+
+// fresh reports whether entry is still within its CacheTTL window and so
+// can be served without revalidating against the server.
+func (entry *CacheEntry) fresh() bool {
+	return entry != nil && !entry.Expires.IsZero() && time.Now().Before(entry.Expires)
+}
+
+// freshUsersPage decodes cached into a users listing if it is still
+// fresh, per CacheEntry.fresh.
+func freshUsersPage(cached *CacheEntry) (*ResponsePagination, []User, bool) {
+	if !cached.fresh() {
+		return nil, nil, false
+	}
+	var page cachedUsersPage
+	if err := json.Unmarshal(cached.Value, &page); err != nil {
+		return nil, nil, false
+	}
+	return page.Pagination, page.Users, true
+}
+
+// freshRelationship decodes cached into a Relationship if it is still
+// fresh, per CacheEntry.fresh.
+func freshRelationship(cached *CacheEntry) (*Relationship, bool) {
+	if !cached.fresh() {
+		return nil, false
+	}
+	rel := new(Relationship)
+	if err := json.Unmarshal(cached.Value, rel); err != nil {
+		return nil, false
+	}
+	return rel, true
+}
+
+// cachedUsersRequest fetches u, the listing of users at a relationship
+// endpoint, through the Client's Cache when one is configured. A cached
+// entry still within its CacheTTL is served directly, with no request
+// sent at all. Once it falls outside that window, if the Cache still
+// returns the entry (see CacheEntry), the previously observed ETag is
+// sent as If-None-Match, and on a 304 response the
+// cached slice is returned together with the ResponsePagination observed
+// when it was stored.
+func (s *RelationshipsService) cachedUsersRequest(u string) ([]User, *ResponsePagination, error) {
+	cache := s.client.Cache
+	key := cacheKey(s.client, u)
+
+	var cached *CacheEntry
+	if cache != nil {
+		cached, _ = cache.Get(key)
+	}
+
+	if page, users, ok := freshUsersPage(cached); ok {
+		return users, page, nil
+	}
+
+	req, err := s.client.NewRequest("GET", u, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	if cached != nil && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	users := new([]User)
+	resp, err := s.client.Do(req, users)
+
+	if cached != nil && resp != nil && resp.StatusCode == http.StatusNotModified {
+		var page cachedUsersPage
+		if jsonErr := json.Unmarshal(cached.Value, &page); jsonErr == nil {
+			return page.Users, page.Pagination, nil
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page := new(ResponsePagination)
+	if s.client.Response.Pagination != nil {
+		page = s.client.Response.Pagination
+	}
+
+	if cache != nil {
+		if raw, jsonErr := json.Marshal(cachedUsersPage{Users: *users, Pagination: page}); jsonErr == nil {
+			cache.Set(key, &CacheEntry{Value: raw, ETag: etagOf(resp)}, s.client.CacheTTL)
+		}
+	}
+
+	return *users, page, err
+}
+
+// cachedRelationship fetches the relationship to userID through the
+// Client's Cache, following the same within-TTL short-circuit and
+// ETag/If-None-Match fallback as cachedUsersRequest.
+func (s *RelationshipsService) cachedRelationship(userID string) (*Relationship, error) {
+	cache := s.client.Cache
+	u := fmt.Sprintf("users/%v/relationship", userID)
+	key := cacheKey(s.client, u)
+
+	var cached *CacheEntry
+	if cache != nil {
+		cached, _ = cache.Get(key)
+	}
+
+	if rel, ok := freshRelationship(cached); ok {
+		return rel, nil
+	}
+
+	req, err := s.client.NewRequest("GET", u, "")
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	rel := new(Relationship)
+	resp, err := s.client.Do(req, rel)
+
+	if cached != nil && resp != nil && resp.StatusCode == http.StatusNotModified {
+		cachedRel := new(Relationship)
+		if jsonErr := json.Unmarshal(cached.Value, cachedRel); jsonErr == nil {
+			return cachedRel, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cache != nil {
+		if raw, jsonErr := json.Marshal(rel); jsonErr == nil {
+			cache.Set(key, &CacheEntry{Value: raw, ETag: etagOf(resp)}, s.client.CacheTTL)
+		}
+	}
+
+	return rel, err
+}
+
+func etagOf(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+	return resp.Header.Get("ETag")
+}
+
+// LRUCache is an in-memory Cache that evicts the least recently used
+// entry once it holds more than Capacity entries. Entries also expire
+// according to the TTL passed to Set, checked lazily on Get.
+type LRUCache struct {
+	Capacity int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewLRUCache returns an LRUCache that holds at most capacity entries.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		Capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the cached entry for key, if present and not expired.
+func (c *LRUCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	item := el.Value.(*lruItem)
+	if !item.entry.Expires.IsZero() && time.Now().After(item.entry.Expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	return item.entry, true
+}
+
+// Set stores entry under key, expiring it after ttl (0 means it never
+// expires on its own, though it can still be evicted for capacity).
+func (c *LRUCache) Set(key string, entry *CacheEntry, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ttl > 0 {
+		entry.Expires = time.Now().Add(ttl)
+	}
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*lruItem).entry = entry
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&lruItem{key: key, entry: entry})
+	c.entries[key] = el
+
+	if c.Capacity > 0 && c.order.Len() > c.Capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*lruItem).key)
+		}
+	}
+}
+
+// Delete removes the cached entry for key, if any.
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+}
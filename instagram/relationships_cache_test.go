@@ -0,0 +1,139 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+
+	if _, ok := c.Get("missing"); ok {
+		t.Fatalf("Get on empty cache should miss")
+	}
+
+	c.Set("a", &CacheEntry{Value: []byte("1")}, 0)
+	c.Set("b", &CacheEntry{Value: []byte("2")}, 0)
+	c.Set("c", &CacheEntry{Value: []byte("3")}, 0) // evicts "a", the least recently used
+
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("expected \"a\" to have been evicted")
+	}
+	if e, ok := c.Get("b"); !ok || string(e.Value) != "2" {
+		t.Errorf("expected \"b\" to still be cached")
+	}
+
+	c.Delete("b")
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("expected \"b\" to have been deleted")
+	}
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	c := NewLRUCache(10)
+	c.Set("a", &CacheEntry{Value: []byte("1")}, time.Nanosecond)
+
+	time.Sleep(time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("expected expired entry to miss")
+	}
+}
+
+// newTestClient returns a Client, with an LRUCache, pointed at a test
+// server that runs handler, together with that server. Tests count the
+// requests that reach the server inside their own handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *httptest.Server) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	base, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	c := NewClient(nil)
+	c.BaseURL = base
+	c.Cache = NewLRUCache(10)
+	return c, server
+}
+
+func TestCachedUsersRequestRevalidatesOn304(t *testing.T) {
+	requests := 0
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == "the-etag" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", "the-etag")
+		body, _ := json.Marshal(struct {
+			Data []User `json:"data"`
+		}{Data: []User{{ID: "1"}}})
+		w.Write(body)
+	}
+
+	c, _ := newTestClient(t, handler)
+
+	first, _, err := c.Relationships.Follows(nil)
+	if err != nil {
+		t.Fatalf("first Follows: %v", err)
+	}
+	if len(first) != 1 || first[0].ID != "1" {
+		t.Fatalf("first Follows = %+v, want one user with ID 1", first)
+	}
+
+	second, _, err := c.Relationships.Follows(nil)
+	if err != nil {
+		t.Fatalf("second Follows: %v", err)
+	}
+	if len(second) != 1 || second[0].ID != "1" {
+		t.Fatalf("second Follows = %+v, want the cached user with ID 1", second)
+	}
+
+	if requests != 2 {
+		t.Fatalf("expected 2 requests to the server (one per call), got %d", requests)
+	}
+}
+
+func TestCachedUsersRequestShortCircuitsWithinTTL(t *testing.T) {
+	requests := 0
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := json.Marshal(struct {
+			Data []User `json:"data"`
+		}{Data: []User{{ID: "1"}}})
+		w.Write(body)
+	}
+
+	c, _ := newTestClient(t, handler)
+	c.CacheTTL = time.Minute
+
+	for i := 0; i < 3; i++ {
+		users, _, err := c.Relationships.Follows(nil)
+		if err != nil {
+			t.Fatalf("Follows call %d: %v", i, err)
+		}
+		if len(users) != 1 || users[0].ID != "1" {
+			t.Fatalf("Follows call %d = %+v, want one user with ID 1", i, users)
+		}
+	}
+
+	if requests != 1 {
+		t.Fatalf("expected only the first call to reach the server within CacheTTL, got %d requests", requests)
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// GraphSnapshot records the authenticated user's followers and followings
+// at a point in time, as captured by Graph.Sync.
+type GraphSnapshot struct {
+	Timestamp time.Time
+	Followers []User
+	Following []User
+}
+
+// GraphStore persists GraphSnapshots so Graph.Sync can diff against the
+// previous sync and callers can inspect history. NewGraph uses an
+// in-memory store when given nil; a GraphStore can also be backed by
+// Redis, BoltDB, SQL, or anything else that can hold a timestamped list
+// of snapshots.
+type GraphStore interface {
+	// Save appends snapshot to the store.
+	Save(ctx context.Context, snapshot *GraphSnapshot) error
+
+	// Latest returns the most recently saved snapshot, or nil if the
+	// store is empty.
+	Latest(ctx context.Context) (*GraphSnapshot, error)
+
+	// History returns every saved snapshot, ordered oldest to newest.
+	History(ctx context.Context) ([]*GraphSnapshot, error)
+}
+
+// GraphDiff reports how the authenticated user's relationships changed
+// between two consecutive GraphSnapshots.
+type GraphDiff struct {
+	Previous time.Time
+	Current  time.Time
+
+	// Gained are users who started following the authenticated user.
+	Gained []User
+
+	// Lost are users who stopped following the authenticated user.
+	Lost []User
+
+	// NewlyFollowedBack are users the authenticated user follows who
+	// started following back since the previous snapshot.
+	NewlyFollowedBack []User
+
+	// Unfollowed are users the authenticated user stopped following.
+	Unfollowed []User
+}
+
+// Graph snapshots the authenticated user's followers and followings and
+// computes diffs between consecutive snapshots.
+type Graph struct {
+	svc   *RelationshipsService
+	store GraphStore
+}
+
+// NewGraph returns a Graph for s, persisting snapshots to store. If store
+// is nil, an in-memory GraphStore is used.
+func (s *RelationshipsService) NewGraph(store GraphStore) *Graph {
+	if store == nil {
+		store = newMemoryGraphStore()
+	}
+	return &Graph{svc: s, store: store}
+}
+
+// Sync fetches the authenticated user's current followers and followings,
+// saves the resulting snapshot to the Graph's store, and returns a
+// GraphDiff against the previously saved snapshot. On the first call,
+// Previous is the zero time and every follower is reported as Gained.
+func (g *Graph) Sync(ctx context.Context) (*GraphDiff, error) {
+	prev, err := g.store.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	followers, err := g.svc.AllFollowedBy(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	following, err := g.svc.AllFollows(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := &GraphSnapshot{Timestamp: time.Now(), Followers: followers, Following: following}
+	if err := g.store.Save(ctx, cur); err != nil {
+		return nil, err
+	}
+
+	return diffSnapshots(prev, cur), nil
+}
+
+// diffSnapshots computes the GraphDiff between two consecutive snapshots.
+// prev may be nil, in which case every follower is reported as Gained.
+func diffSnapshots(prev, cur *GraphSnapshot) *GraphDiff {
+	if prev == nil {
+		return &GraphDiff{
+			Current: cur.Timestamp,
+			Gained:  cur.Followers,
+		}
+	}
+
+	diff := &GraphDiff{Previous: prev.Timestamp, Current: cur.Timestamp}
+	diff.Gained = usersMissingFrom(cur.Followers, prev.Followers)
+	diff.Lost = usersMissingFrom(prev.Followers, cur.Followers)
+	diff.Unfollowed = usersMissingFrom(prev.Following, cur.Following)
+
+	diff.NewlyFollowedBack = intersectUsers(diff.Gained, cur.Following)
+
+	return diff
+}
+
+// Watch calls Sync on the given interval, emitting a GraphDiff on the
+// returned channel after every sync that succeeds. The channel is closed
+// when ctx is canceled. Sync errors are dropped; callers that need to
+// observe them should call Sync directly instead.
+func (g *Graph) Watch(ctx context.Context, interval time.Duration) <-chan GraphDiff {
+	out := make(chan GraphDiff)
+
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				diff, err := g.Sync(ctx)
+				if err != nil {
+					continue
+				}
+				select {
+				case out <- *diff:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// usersMissingFrom returns the users in a that are not present in b, by ID.
+func usersMissingFrom(a, b []User) []User {
+	present := make(map[string]bool, len(b))
+	for _, u := range b {
+		present[u.ID] = true
+	}
+
+	var missing []User
+	for _, u := range a {
+		if !present[u.ID] {
+			missing = append(missing, u)
+		}
+	}
+	return missing
+}
+
+// intersectUsers returns the users in a that are also present in b, by ID.
+func intersectUsers(a, b []User) []User {
+	present := make(map[string]bool, len(b))
+	for _, u := range b {
+		present[u.ID] = true
+	}
+
+	var common []User
+	for _, u := range a {
+		if present[u.ID] {
+			common = append(common, u)
+		}
+	}
+	return common
+}
+
+// memoryGraphStore is the default in-memory GraphStore.
+type memoryGraphStore struct {
+	mu        sync.Mutex
+	snapshots []*GraphSnapshot
+}
+
+func newMemoryGraphStore() *memoryGraphStore {
+	return &memoryGraphStore{}
+}
+
+func (m *memoryGraphStore) Save(ctx context.Context, snapshot *GraphSnapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshots = append(m.snapshots, snapshot)
+	return nil
+}
+
+func (m *memoryGraphStore) Latest(ctx context.Context) (*GraphSnapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.snapshots) == 0 {
+		return nil, nil
+	}
+	return m.snapshots[len(m.snapshots)-1], nil
+}
+
+func (m *memoryGraphStore) History(ctx context.Context) ([]*GraphSnapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]*GraphSnapshot, len(m.snapshots))
+	copy(out, m.snapshots)
+	sort.Slice(out, func(i, j int) bool { return out[i].Timestamp.Before(out[j].Timestamp) })
+	return out, nil
+}
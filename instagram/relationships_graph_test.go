@@ -0,0 +1,102 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func userIDs(users []User) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return ids
+}
+
+func TestDiffSnapshotsFirstSync(t *testing.T) {
+	cur := &GraphSnapshot{
+		Timestamp: time.Unix(1, 0),
+		Followers: []User{{ID: "1"}, {ID: "2"}},
+	}
+
+	diff := diffSnapshots(nil, cur)
+
+	if got, want := userIDs(diff.Gained), []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Gained = %v, want %v", got, want)
+	}
+	if len(diff.Lost) != 0 || len(diff.Unfollowed) != 0 || len(diff.NewlyFollowedBack) != 0 {
+		t.Errorf("expected only Gained to be populated on the first sync, got %+v", diff)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	prev := &GraphSnapshot{
+		Timestamp: time.Unix(1, 0),
+		Followers: []User{{ID: "alice"}, {ID: "bob"}},
+		Following: []User{{ID: "carol"}, {ID: "dave"}},
+	}
+	cur := &GraphSnapshot{
+		Timestamp: time.Unix(2, 0),
+		// bob stopped following us, eve started.
+		Followers: []User{{ID: "alice"}, {ID: "eve"}},
+		// we unfollowed dave, and started following eve, who already follows us.
+		Following: []User{{ID: "carol"}, {ID: "eve"}},
+	}
+
+	diff := diffSnapshots(prev, cur)
+
+	if got, want := userIDs(diff.Gained), []string{"eve"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Gained = %v, want %v", got, want)
+	}
+	if got, want := userIDs(diff.Lost), []string{"bob"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lost = %v, want %v", got, want)
+	}
+	if got, want := userIDs(diff.Unfollowed), []string{"dave"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unfollowed = %v, want %v", got, want)
+	}
+	if got, want := userIDs(diff.NewlyFollowedBack), []string{"eve"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NewlyFollowedBack = %v, want %v", got, want)
+	}
+}
+
+// TestDiffSnapshotsNewlyFollowedBack disambiguates "a new follower I
+// already follow" from "someone I newly followed who already followed
+// me" — cases that TestDiffSnapshots can't tell apart because its only
+// changed user, eve, is both at once.
+func TestDiffSnapshotsNewlyFollowedBack(t *testing.T) {
+	prev := &GraphSnapshot{
+		Timestamp: time.Unix(1, 0),
+		// I already follow xavier, who does not yet follow me back.
+		Following: []User{{ID: "xavier"}},
+		// yara already follows me, but I don't follow her yet.
+		Followers: []User{{ID: "yara"}},
+	}
+	cur := &GraphSnapshot{
+		Timestamp: time.Unix(2, 0),
+		// xavier follows back; I follow yara. Only the "following" side
+		// changes for yara, and only the "follower" side changes for
+		// xavier.
+		Following: []User{{ID: "xavier"}, {ID: "yara"}},
+		Followers: []User{{ID: "yara"}, {ID: "xavier"}},
+	}
+
+	diff := diffSnapshots(prev, cur)
+
+	if got, want := userIDs(diff.Gained), []string{"xavier"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Gained = %v, want %v", got, want)
+	}
+
+	// Only xavier's follow-back is a genuine "newly followed back" event:
+	// he was already someone I follow and just started following me.
+	// yara must NOT appear: she already followed me, I merely chose to
+	// follow her, which is not her following back.
+	if got, want := userIDs(diff.NewlyFollowedBack), []string{"xavier"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NewlyFollowedBack = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import "context"
+
+// pageFetcher fetches a single page of users, given the pagination cursor
+// returned by the previous call (nil for the first page).
+type pageFetcher func(oPagination *ResponsePagination) ([]User, *ResponsePagination, error)
+
+// UserIterator lazily walks a paginated listing of users, issuing one
+// request per page as the caller advances through it. It replaces the
+// pattern of callers manually looping over *ResponsePagination.
+type UserIterator struct {
+	ctx   context.Context
+	fetch pageFetcher
+	page  *ResponsePagination
+
+	users []User
+	index int
+
+	started bool
+	done    bool
+	err     error
+}
+
+func newUserIterator(ctx context.Context, fetch pageFetcher) *UserIterator {
+	return &UserIterator{ctx: ctx, fetch: fetch, index: -1}
+}
+
+// Next advances the iterator to the next user, fetching additional pages
+// from the API as needed. It returns false when iteration is finished,
+// either because there are no more users or because an error occurred
+// (including context cancellation), in which case Err returns the cause.
+func (it *UserIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	it.index++
+	if it.index < len(it.users) {
+		return true
+	}
+	if it.started && it.done {
+		return false
+	}
+
+	users, page, err := it.fetch(it.page)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.started = true
+	it.users = users
+	it.index = 0
+	it.page = page
+	it.done = page == nil || page.NextURL == ""
+
+	return len(it.users) > 0
+}
+
+// User returns the user at the iterator's current position. It must only
+// be called after a call to Next has returned true.
+func (it *UserIterator) User() User {
+	return it.users[it.index]
+}
+
+// Err returns the first error encountered by the iterator, if any.
+func (it *UserIterator) Err() error {
+	return it.err
+}
+
+// FollowsIter returns an iterator over the users the authenticated user
+// follows, fetching pages on demand as the caller advances it.
+func (s *RelationshipsService) FollowsIter(ctx context.Context) *UserIterator {
+	return newUserIterator(ctx, s.Follows)
+}
+
+// FollowedByIter returns an iterator over the users who follow the
+// authenticated user, fetching pages on demand as the caller advances it.
+func (s *RelationshipsService) FollowedByIter(ctx context.Context) *UserIterator {
+	return newUserIterator(ctx, s.FollowedBy)
+}
+
+// AllOptions controls the behavior of the All* helpers below.
+type AllOptions struct {
+	// MaxPages caps the number of pages fetched, 0 means no limit.
+	MaxPages int
+}
+
+func drain(ctx context.Context, fetch pageFetcher, opts *AllOptions) ([]User, error) {
+	it := newUserIterator(ctx, fetch)
+
+	var all []User
+	pages := 0
+	for it.Next() {
+		all = append(all, it.User())
+		if it.index == len(it.users)-1 {
+			pages++
+			if opts != nil && opts.MaxPages > 0 && pages >= opts.MaxPages {
+				break
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return all, err
+	}
+	return all, ctx.Err()
+}
+
+// AllFollows walks every page of users the authenticated user follows and
+// returns them as a single slice, honoring ctx cancellation between pages.
+//
+// Instagram API docs: http://instagram.com/developer/endpoints/relationships/#get_users_follows
+func (s *RelationshipsService) AllFollows(ctx context.Context, opts *AllOptions) ([]User, error) {
+	return drain(ctx, s.Follows, opts)
+}
+
+// AllFollowedBy walks every page of users who follow the authenticated user
+// and returns them as a single slice, honoring ctx cancellation between pages.
+//
+// Instagram API docs: http://instagram.com/developer/endpoints/relationships/#get_users_followed_by
+func (s *RelationshipsService) AllFollowedBy(ctx context.Context, opts *AllOptions) ([]User, error) {
+	return drain(ctx, s.FollowedBy, opts)
+}
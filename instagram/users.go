@@ -0,0 +1,16 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+// User represents an Instagram user.
+type User struct {
+	ID             string `json:"id,omitempty"`
+	Username       string `json:"username,omitempty"`
+	FullName       string `json:"full_name,omitempty"`
+	ProfilePicture string `json:"profile_picture,omitempty"`
+	Bio            string `json:"bio,omitempty"`
+	Website        string `json:"website,omitempty"`
+}